@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"fmt"
+)
+
+import (
+	"github.com/apache/dubbo-go/metadata/report"
+)
+
+var metadataReportFactories = make(map[string]func() report.MetadataReportFactory)
+
+// SetMetadataReportFactory registers a MetadataReportFactory constructor under name so it
+// can later be selected via the `metadata-type` url parameter, e.g. "consul" or "etcd".
+// Backend implementations call this from their package init().
+func SetMetadataReportFactory(name string, v func() report.MetadataReportFactory) {
+	metadataReportFactories[name] = v
+}
+
+// GetMetadataReportFactory returns the MetadataReportFactory registered under name. It returns
+// an error rather than panicking when name has not been registered: metadata-type is ordinary
+// application config, and a typo or a missing blank-import of the backend package shouldn't be
+// able to bring the whole process down - the caller is expected to surface this error instead.
+func GetMetadataReportFactory(name string) (report.MetadataReportFactory, error) {
+	if metadataReportFactories[name] == nil {
+		return nil, fmt.Errorf("metadata report factory for %q is not registered - blank-import its backend package (e.g. github.com/apache/dubbo-go/metadata/report/%s)", name, name)
+	}
+	return metadataReportFactories[name](), nil
+}
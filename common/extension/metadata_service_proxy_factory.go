@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"fmt"
+)
+
+import (
+	"github.com/apache/dubbo-go/metadata/service/proxy"
+)
+
+var metadataServiceProxyFactories = make(map[string]func() proxy.MetadataServiceProxyFactory)
+
+// SetMetadataServiceProxyFactory registers a MetadataServiceProxyFactory constructor under name.
+func SetMetadataServiceProxyFactory(name string, v func() proxy.MetadataServiceProxyFactory) {
+	metadataServiceProxyFactories[name] = v
+}
+
+// GetMetadataServiceProxyFactory returns the MetadataServiceProxyFactory registered under name.
+// It returns an error rather than panicking when name has not been registered, matching
+// GetMetadataReportFactory in this package: an unregistered metadata-service-proxy-factory name
+// is ordinary application config, and the caller is expected to surface the error instead of
+// the process going down over a typo or a missing blank-import.
+func GetMetadataServiceProxyFactory(name string) (proxy.MetadataServiceProxyFactory, error) {
+	if metadataServiceProxyFactories[name] == nil {
+		return nil, fmt.Errorf("metadata service proxy factory for %q is not registered - blank-import its package first", name)
+	}
+	return metadataServiceProxyFactories[name](), nil
+}
@@ -0,0 +1,207 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consul
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/hashicorp/consul/api"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/common/logger"
+	"github.com/apache/dubbo-go/metadata/definition"
+	"github.com/apache/dubbo-go/metadata/identifier"
+	"github.com/apache/dubbo-go/metadata/report"
+)
+
+// consul is the name this backend is registered under, selected via the
+// `metadata-type=consul` url parameter.
+const consul = "consul"
+
+func init() {
+	extension.SetMetadataReportFactory(consul, newConsulMetadataReportFactory)
+}
+
+func newConsulMetadataReportFactory() report.MetadataReportFactory {
+	return &consulMetadataReportFactory{}
+}
+
+type consulMetadataReportFactory struct{}
+
+// CreateMetadataReport builds a metadataReport talking to the consul agent at url.Location.
+func (f *consulMetadataReportFactory) CreateMetadataReport(url *common.URL) (report.MetadataReport, error) {
+	config := api.DefaultConfig()
+	config.Address = url.Location
+	client, err := api.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return &metadataReport{
+		kv:       client.KV(),
+		watchers: make(map[string]map[report.MetadataChangeListener]chan struct{}),
+	}, nil
+}
+
+// metadataReport stores metadata as plain JSON values in consul's key/value store,
+// keyed by the identifier's GetIdentifierKey.
+type metadataReport struct {
+	kv *api.KV
+
+	watchersMu sync.Mutex
+	// watchers maps a watched key to the stop channel of each subscribed listener's blocking
+	// query goroutine, so Unsubscribe can end it.
+	watchers map[string]map[report.MetadataChangeListener]chan struct{}
+}
+
+func (m *metadataReport) StoreProviderMetadata(id *identifier.MetadataIdentifier, sd *definition.ServiceDefinition) error {
+	data, err := sd.ToBytes()
+	if err != nil {
+		return err
+	}
+	return m.put(id.GetIdentifierKey(), data)
+}
+
+func (m *metadataReport) StoreConsumerMetadata(id *identifier.MetadataIdentifier, params map[string]string) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return m.put(id.GetIdentifierKey(), data)
+}
+
+func (m *metadataReport) PublishAppMetadata(id *identifier.SubscriberMetadataIdentifier, info *common.MetadataInfo) error {
+	data, err := report.MarshalMetadataInfo(info)
+	if err != nil {
+		return err
+	}
+	return m.put(id.GetIdentifierKey(), data)
+}
+
+func (m *metadataReport) GetAppMetadata(id *identifier.SubscriberMetadataIdentifier) (*common.MetadataInfo, error) {
+	pair, _, err := m.kv.Get(id.GetIdentifierKey(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil {
+		return nil, fmt.Errorf("no metadata found for %s", id.GetIdentifierKey())
+	}
+	return report.UnmarshalMetadataInfo(pair.Value)
+}
+
+func (m *metadataReport) StoreMetadataDelta(id *identifier.SubscriberMetadataIdentifier, delta *report.MetadataDelta) error {
+	data, err := report.MarshalMetadataDelta(delta)
+	if err != nil {
+		return err
+	}
+	return m.put(id.GetIdentifierKey(), data)
+}
+
+func (m *metadataReport) GetMetadataDelta(id *identifier.SubscriberMetadataIdentifier) (*report.MetadataDelta, error) {
+	pair, _, err := m.kv.Get(id.GetIdentifierKey(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil {
+		return nil, fmt.Errorf("no metadata delta found for %s", id.GetIdentifierKey())
+	}
+	return report.UnmarshalMetadataDelta(pair.Value)
+}
+
+func (m *metadataReport) PublishRevisionPointer(id *identifier.SubscriberMetadataIdentifier, pointer *report.RevisionPointer) error {
+	data, err := json.Marshal(pointer)
+	if err != nil {
+		return err
+	}
+	return m.put(id.GetIdentifierKey(), data)
+}
+
+// Subscribe starts a goroutine that blocks on consul's KV.Get using WaitIndex (a long-poll
+// query) on id's key, invoking listener.OnEvent every time the stored RevisionPointer changes.
+func (m *metadataReport) Subscribe(id *identifier.SubscriberMetadataIdentifier, listener report.MetadataChangeListener) error {
+	key := id.GetIdentifierKey()
+	stop := make(chan struct{})
+
+	m.watchersMu.Lock()
+	if m.watchers[key] == nil {
+		m.watchers[key] = make(map[report.MetadataChangeListener]chan struct{})
+	}
+	m.watchers[key][listener] = stop
+	m.watchersMu.Unlock()
+
+	go m.watch(key, listener, stop)
+	return nil
+}
+
+func (m *metadataReport) watch(key string, listener report.MetadataChangeListener, stop chan struct{}) {
+	var waitIndex uint64
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		pair, meta, err := m.kv.Get(key, &api.QueryOptions{WaitIndex: waitIndex})
+		if err != nil {
+			logger.Errorf("watching consul key %s error[%v]", key, err)
+			time.Sleep(time.Second)
+			continue
+		}
+		waitIndex = meta.LastIndex
+		if pair == nil {
+			continue
+		}
+
+		pointer := &report.RevisionPointer{}
+		if err := json.Unmarshal(pair.Value, pointer); err != nil {
+			logger.Errorf("unmarshalling revision pointer for %s error[%v]", key, err)
+			continue
+		}
+		if err := listener.OnEvent(pointer.Revision); err != nil {
+			logger.Errorf("notifying metadata change listener for %s error[%v]", key, err)
+		}
+	}
+}
+
+// Unsubscribe stops the watch goroutine started by Subscribe for listener.
+func (m *metadataReport) Unsubscribe(id *identifier.SubscriberMetadataIdentifier, listener report.MetadataChangeListener) error {
+	key := id.GetIdentifierKey()
+
+	m.watchersMu.Lock()
+	defer m.watchersMu.Unlock()
+	if stops, ok := m.watchers[key]; ok {
+		if stop, ok := stops[listener]; ok {
+			close(stop)
+			delete(stops, listener)
+		}
+	}
+	return nil
+}
+
+func (m *metadataReport) put(key string, data []byte) error {
+	_, err := m.kv.Put(&api.KVPair{Key: key, Value: data}, nil)
+	return err
+}
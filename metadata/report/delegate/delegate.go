@@ -0,0 +1,194 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delegate
+
+import (
+	"fmt"
+	"strings"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/constant"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/common/logger"
+	"github.com/apache/dubbo-go/config"
+	"github.com/apache/dubbo-go/metadata/definition"
+	"github.com/apache/dubbo-go/metadata/identifier"
+	"github.com/apache/dubbo-go/metadata/report"
+)
+
+// defaultMetadataReportType is used when the application has not set metadata-type,
+// preserving the zookeeper-only behaviour this package used to hardcode. No package in this
+// tree registers "zookeeper" yet, so leaving metadata-type unset surfaces the same clear,
+// non-panicking config error as naming any other unregistered type (see
+// extension.GetMetadataReportFactory) - blank-import a backend package, such as
+// metadata/report/consul or metadata/report/etcd, or set metadata-type explicitly.
+const defaultMetadataReportType = "zookeeper"
+
+// MetadataReport fans a single logical report call out to one or more pluggable
+// report.MetadataReport backends. Which ones are used is controlled by the
+// `metadata-type` parameter of the metadata report url, e.g. "consul", "etcd" or
+// "consul,etcd" to dual-write both stores while migrating between them.
+type MetadataReport struct {
+	reports []report.MetadataReport
+}
+
+// NewMetadataReport builds a MetadataReport from the application's metadata report
+// config, resolving each name in `metadata-type` against
+// extension.GetMetadataReportFactory and keeping every one of them so
+// StoreProviderMetadata, StoreConsumerMetadata and PublishAppMetadata are replicated across
+// all configured backends, while GetAppMetadata returns the first successful read.
+func NewMetadataReport() (*MetadataReport, error) {
+	url, err := config.GetMetadataReportConfig().ToURL()
+	if err != nil {
+		return nil, err
+	}
+
+	types := strings.Split(url.GetParam(constant.METADATA_REPORT_TYPE_KEY, defaultMetadataReportType), ",")
+	reports := make([]report.MetadataReport, 0, len(types))
+	for _, t := range types {
+		t = strings.TrimSpace(t)
+		if len(t) == 0 {
+			continue
+		}
+		factory, err := extension.GetMetadataReportFactory(t)
+		if err != nil {
+			return nil, fmt.Errorf("building metadata report for metadata-type %q: %w", t, err)
+		}
+		mr, err := factory.CreateMetadataReport(url)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, mr)
+	}
+
+	return &MetadataReport{reports: reports}, nil
+}
+
+// StoreProviderMetadata stores the ServiceDefinition of a provider url on every configured backend.
+func (m *MetadataReport) StoreProviderMetadata(id *identifier.MetadataIdentifier, sd *definition.ServiceDefinition) error {
+	for _, r := range m.reports {
+		if err := r.StoreProviderMetadata(id, sd); err != nil {
+			logger.Errorf("StoreProviderMetadata error[%v]", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// StoreConsumerMetadata stores the parameters of a consumer url on every configured backend.
+func (m *MetadataReport) StoreConsumerMetadata(id *identifier.MetadataIdentifier, params map[string]string) error {
+	for _, r := range m.reports {
+		if err := r.StoreConsumerMetadata(id, params); err != nil {
+			logger.Errorf("StoreConsumerMetadata error[%v]", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// PublishAppMetadata publishes the MetadataInfo of an application to every configured backend.
+func (m *MetadataReport) PublishAppMetadata(id *identifier.SubscriberMetadataIdentifier, info *common.MetadataInfo) error {
+	for _, r := range m.reports {
+		if err := r.PublishAppMetadata(id, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAppMetadata fetches the MetadataInfo for an application revision, returning the
+// first successful read across the configured backends.
+func (m *MetadataReport) GetAppMetadata(id *identifier.SubscriberMetadataIdentifier) (*common.MetadataInfo, error) {
+	var lastErr error
+	for _, r := range m.reports {
+		info, err := r.GetAppMetadata(id)
+		if err == nil {
+			return info, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// StoreMetadataDelta stores the incremental MetadataDelta on every configured backend.
+func (m *MetadataReport) StoreMetadataDelta(id *identifier.SubscriberMetadataIdentifier, delta *report.MetadataDelta) error {
+	for _, r := range m.reports {
+		if err := r.StoreMetadataDelta(id, delta); err != nil {
+			logger.Errorf("StoreMetadataDelta error[%v]", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMetadataDelta fetches the MetadataDelta for an application revision, returning the
+// first successful read across the configured backends.
+func (m *MetadataReport) GetMetadataDelta(id *identifier.SubscriberMetadataIdentifier) (*report.MetadataDelta, error) {
+	var lastErr error
+	for _, r := range m.reports {
+		delta, err := r.GetMetadataDelta(id)
+		if err == nil {
+			return delta, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// PublishRevisionPointer updates the service's revision pointer on every configured backend.
+func (m *MetadataReport) PublishRevisionPointer(id *identifier.SubscriberMetadataIdentifier, pointer *report.RevisionPointer) error {
+	for _, r := range m.reports {
+		if err := r.PublishRevisionPointer(id, pointer); err != nil {
+			logger.Errorf("PublishRevisionPointer error[%v]", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe watches the service's revision pointer on every configured backend. Each backend's
+// watch invokes listener on its own, so with more than one backend a single change reaches
+// listener once per backend, possibly concurrently. If a backend fails
+// after earlier ones already registered a watch, Subscribe unsubscribes those before returning
+// the error, so the caller isn't left with an orphaned watch goroutine it has no handle to
+// cancel.
+func (m *MetadataReport) Subscribe(id *identifier.SubscriberMetadataIdentifier, listener report.MetadataChangeListener) error {
+	for i, r := range m.reports {
+		if err := r.Subscribe(id, listener); err != nil {
+			for _, registered := range m.reports[:i] {
+				if unsubErr := registered.Unsubscribe(id, listener); unsubErr != nil {
+					logger.Errorf("Unsubscribe error[%v] while rolling back a partially failed Subscribe", unsubErr)
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Unsubscribe cancels a previously registered Subscribe watch on every configured backend.
+func (m *MetadataReport) Unsubscribe(id *identifier.SubscriberMetadataIdentifier, listener report.MetadataChangeListener) error {
+	for _, r := range m.reports {
+		if err := r.Unsubscribe(id, listener); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+import (
+	"go.etcd.io/etcd/clientv3"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/common/logger"
+	"github.com/apache/dubbo-go/metadata/definition"
+	"github.com/apache/dubbo-go/metadata/identifier"
+	"github.com/apache/dubbo-go/metadata/report"
+)
+
+// etcd is the name this backend is registered under, selected via the
+// `metadata-type=etcd` url parameter.
+const etcd = "etcd"
+
+// dialTimeout bounds how long CreateMetadataReport waits for the etcd client to connect.
+const dialTimeout = 3 * time.Second
+
+func init() {
+	extension.SetMetadataReportFactory(etcd, newEtcdMetadataReportFactory)
+}
+
+func newEtcdMetadataReportFactory() report.MetadataReportFactory {
+	return &etcdMetadataReportFactory{}
+}
+
+type etcdMetadataReportFactory struct{}
+
+// CreateMetadataReport builds a metadataReport talking to the etcd cluster at url.Location.
+func (f *etcdMetadataReportFactory) CreateMetadataReport(url *common.URL) (report.MetadataReport, error) {
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{url.Location},
+		DialTimeout: dialTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &metadataReport{
+		client:   client,
+		watchers: make(map[string]map[report.MetadataChangeListener]context.CancelFunc),
+	}, nil
+}
+
+// metadataReport stores metadata as plain JSON values under the identifier's
+// GetIdentifierKey in etcd.
+type metadataReport struct {
+	client *clientv3.Client
+
+	watchersMu sync.Mutex
+	// watchers maps a watched key to the cancel func of each subscribed listener's watch goroutine.
+	watchers map[string]map[report.MetadataChangeListener]context.CancelFunc
+}
+
+func (m *metadataReport) StoreProviderMetadata(id *identifier.MetadataIdentifier, sd *definition.ServiceDefinition) error {
+	data, err := sd.ToBytes()
+	if err != nil {
+		return err
+	}
+	return m.put(id.GetIdentifierKey(), string(data))
+}
+
+func (m *metadataReport) StoreConsumerMetadata(id *identifier.MetadataIdentifier, params map[string]string) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return m.put(id.GetIdentifierKey(), string(data))
+}
+
+func (m *metadataReport) PublishAppMetadata(id *identifier.SubscriberMetadataIdentifier, info *common.MetadataInfo) error {
+	data, err := report.MarshalMetadataInfo(info)
+	if err != nil {
+		return err
+	}
+	return m.put(id.GetIdentifierKey(), string(data))
+}
+
+func (m *metadataReport) GetAppMetadata(id *identifier.SubscriberMetadataIdentifier) (*common.MetadataInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	resp, err := m.client.Get(ctx, id.GetIdentifierKey())
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, fmt.Errorf("no metadata found for %s", id.GetIdentifierKey())
+	}
+	return report.UnmarshalMetadataInfo(resp.Kvs[0].Value)
+}
+
+func (m *metadataReport) StoreMetadataDelta(id *identifier.SubscriberMetadataIdentifier, delta *report.MetadataDelta) error {
+	data, err := report.MarshalMetadataDelta(delta)
+	if err != nil {
+		return err
+	}
+	return m.put(id.GetIdentifierKey(), string(data))
+}
+
+func (m *metadataReport) GetMetadataDelta(id *identifier.SubscriberMetadataIdentifier) (*report.MetadataDelta, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	resp, err := m.client.Get(ctx, id.GetIdentifierKey())
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, fmt.Errorf("no metadata delta found for %s", id.GetIdentifierKey())
+	}
+	return report.UnmarshalMetadataDelta(resp.Kvs[0].Value)
+}
+
+func (m *metadataReport) PublishRevisionPointer(id *identifier.SubscriberMetadataIdentifier, pointer *report.RevisionPointer) error {
+	data, err := json.Marshal(pointer)
+	if err != nil {
+		return err
+	}
+	return m.put(id.GetIdentifierKey(), string(data))
+}
+
+// Subscribe opens a native etcd watch on id's key, invoking listener.OnEvent with the new
+// revision every time the stored RevisionPointer changes.
+func (m *metadataReport) Subscribe(id *identifier.SubscriberMetadataIdentifier, listener report.MetadataChangeListener) error {
+	key := id.GetIdentifierKey()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m.watchersMu.Lock()
+	if m.watchers[key] == nil {
+		m.watchers[key] = make(map[report.MetadataChangeListener]context.CancelFunc)
+	}
+	m.watchers[key][listener] = cancel
+	m.watchersMu.Unlock()
+
+	watchCh := m.client.Watch(ctx, key)
+	go m.watch(key, listener, watchCh)
+	return nil
+}
+
+func (m *metadataReport) watch(key string, listener report.MetadataChangeListener, watchCh clientv3.WatchChan) {
+	for resp := range watchCh {
+		for _, event := range resp.Events {
+			if event.Type != clientv3.EventTypePut {
+				continue
+			}
+			pointer := &report.RevisionPointer{}
+			if err := json.Unmarshal(event.Kv.Value, pointer); err != nil {
+				logger.Errorf("unmarshalling revision pointer for %s error[%v]", key, err)
+				continue
+			}
+			if err := listener.OnEvent(pointer.Revision); err != nil {
+				logger.Errorf("notifying metadata change listener for %s error[%v]", key, err)
+			}
+		}
+	}
+}
+
+// Unsubscribe cancels the watch context created by Subscribe for listener.
+func (m *metadataReport) Unsubscribe(id *identifier.SubscriberMetadataIdentifier, listener report.MetadataChangeListener) error {
+	key := id.GetIdentifierKey()
+
+	m.watchersMu.Lock()
+	defer m.watchersMu.Unlock()
+	if cancels, ok := m.watchers[key]; ok {
+		if cancel, ok := cancels[listener]; ok {
+			cancel()
+			delete(cancels, listener)
+		}
+	}
+	return nil
+}
+
+func (m *metadataReport) put(key, value string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	_, err := m.client.Put(ctx, key, value)
+	return err
+}
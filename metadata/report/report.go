@@ -0,0 +1,168 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/metadata/definition"
+	"github.com/apache/dubbo-go/metadata/identifier"
+)
+
+// MetadataReport is the extension point every pluggable metadata report
+// backend (zookeeper, nacos, consul, etcd, ...) must implement. It is what
+// metadata/report/delegate dispatches to once it has resolved the
+// `metadata-type` URL parameter to a concrete implementation.
+type MetadataReport interface {
+	// StoreProviderMetadata stores the ServiceDefinition of a provider url
+	StoreProviderMetadata(*identifier.MetadataIdentifier, *definition.ServiceDefinition) error
+	// StoreConsumerMetadata stores the parameters of a consumer url
+	StoreConsumerMetadata(*identifier.MetadataIdentifier, map[string]string) error
+	// PublishAppMetadata publishes the full MetadataInfo of an application under the given revision
+	PublishAppMetadata(*identifier.SubscriberMetadataIdentifier, *common.MetadataInfo) error
+	// GetAppMetadata retrieves the MetadataInfo previously published for an application revision
+	GetAppMetadata(*identifier.SubscriberMetadataIdentifier) (*common.MetadataInfo, error)
+	// StoreMetadataDelta stores an incremental MetadataDelta under the given revision, to be
+	// replayed on top of its ParentRevision's full snapshot by GetMetadataDelta
+	StoreMetadataDelta(*identifier.SubscriberMetadataIdentifier, *MetadataDelta) error
+	// GetMetadataDelta retrieves a previously stored MetadataDelta for an application revision
+	GetMetadataDelta(*identifier.SubscriberMetadataIdentifier) (*MetadataDelta, error)
+	// PublishRevisionPointer updates the service's mutable revision pointer, the key Subscribe
+	// watches for change notifications
+	PublishRevisionPointer(*identifier.SubscriberMetadataIdentifier, *RevisionPointer) error
+	// Subscribe watches the service's revision pointer and invokes listener every time it
+	// changes to a new revision, until Unsubscribe is called for the same listener
+	Subscribe(*identifier.SubscriberMetadataIdentifier, MetadataChangeListener) error
+	// Unsubscribe cancels a previously registered Subscribe watch
+	Unsubscribe(*identifier.SubscriberMetadataIdentifier, MetadataChangeListener) error
+}
+
+// MetadataChangeListener is notified with the new revision whenever a watched application's
+// RevisionPointer changes. It carries only the revision, not the MetadataInfo itself, since the
+// backend observing the change has no way to reassemble a delta chain on its own; callers use
+// the revision to fetch (or reassemble) the MetadataInfo through RemoteMetadataService.
+type MetadataChangeListener interface {
+	OnEvent(revision string) error
+}
+
+// RevisionPointer is the small mutable document Subscribe watches: it only carries the
+// currently active revision, pointing watchers at the immutable MetadataInfo (or MetadataDelta
+// chain) published under PublishAppMetadata / StoreMetadataDelta for that revision.
+type RevisionPointer struct {
+	Revision string
+}
+
+// MetadataDelta carries the service entries that changed between a parent full MetadataInfo
+// snapshot and the revision it is published under, so large applications can republish a
+// cheap delta instead of the full MetadataInfo on every change.
+type MetadataDelta struct {
+	// ParentRevision is the revision of the full MetadataInfo this delta applies on top of
+	ParentRevision string
+	// Added holds service entries that did not exist in ParentRevision
+	Added map[string]*common.ServiceInfo
+	// Changed holds service entries that existed in ParentRevision with a different value
+	Changed map[string]*common.ServiceInfo
+	// Removed holds the service keys present in ParentRevision but absent now
+	Removed []string
+}
+
+// PublishAppMetadata and StoreMetadataDelta write to the same
+// identifier.SubscriberMetadataIdentifier key namespace - a revision is published as a full
+// MetadataInfo snapshot or an incremental MetadataDelta depending on fullSnapshotInterval, not
+// on anything in the key itself. MetadataInfo and MetadataDelta share no JSON field names, so
+// unmarshalling one's bytes into the other's struct silently succeeds with a zero value instead
+// of erroring. metadataPayload envelopes the marshaled value with a kind discriminator so
+// UnmarshalMetadataInfo/UnmarshalMetadataDelta can tell which one they actually got back and
+// error out on a mismatch, instead of a "try A, fall back to B on error" dispatch that never
+// sees an error.
+type metadataPayload struct {
+	Kind  payloadKind     `json:"kind"`
+	Value json.RawMessage `json:"value"`
+}
+
+type payloadKind string
+
+const (
+	metadataInfoPayload  payloadKind = "info"
+	metadataDeltaPayload payloadKind = "delta"
+)
+
+// MarshalMetadataInfo envelopes info as a metadataInfoPayload, for a MetadataReport backend to
+// store under PublishAppMetadata.
+func MarshalMetadataInfo(info *common.MetadataInfo) ([]byte, error) {
+	return marshalPayload(metadataInfoPayload, info)
+}
+
+// UnmarshalMetadataInfo unwraps data previously produced by MarshalMetadataInfo. It errors
+// instead of returning a zero-value MetadataInfo if data actually holds a MarshalMetadataDelta
+// payload.
+func UnmarshalMetadataInfo(data []byte) (*common.MetadataInfo, error) {
+	value, err := unwrapPayload(data, metadataInfoPayload)
+	if err != nil {
+		return nil, err
+	}
+	info := &common.MetadataInfo{}
+	if err := json.Unmarshal(value, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// MarshalMetadataDelta envelopes delta as a metadataDeltaPayload, for a MetadataReport backend
+// to store under StoreMetadataDelta.
+func MarshalMetadataDelta(delta *MetadataDelta) ([]byte, error) {
+	return marshalPayload(metadataDeltaPayload, delta)
+}
+
+// UnmarshalMetadataDelta unwraps data previously produced by MarshalMetadataDelta. It errors
+// instead of returning a zero-value MetadataDelta if data actually holds a MarshalMetadataInfo
+// payload.
+func UnmarshalMetadataDelta(data []byte) (*MetadataDelta, error) {
+	value, err := unwrapPayload(data, metadataDeltaPayload)
+	if err != nil {
+		return nil, err
+	}
+	delta := &MetadataDelta{}
+	if err := json.Unmarshal(value, delta); err != nil {
+		return nil, err
+	}
+	return delta, nil
+}
+
+func marshalPayload(kind payloadKind, v interface{}) ([]byte, error) {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(metadataPayload{Kind: kind, Value: value})
+}
+
+func unwrapPayload(data []byte, want payloadKind) (json.RawMessage, error) {
+	payload := metadataPayload{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Kind != want {
+		return nil, fmt.Errorf("expected a %q metadata payload, got %q", want, payload.Kind)
+	}
+	return payload.Value, nil
+}
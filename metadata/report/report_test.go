@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+)
+
+// TestMarshalMetadataInfoRoundTrip covers MarshalMetadataInfo/UnmarshalMetadataInfo agreeing
+// with each other on a well-formed payload.
+func TestMarshalMetadataInfoRoundTrip(t *testing.T) {
+	info := &common.MetadataInfo{
+		Services: map[string]*common.ServiceInfo{
+			"svc": {Name: "svc"},
+		},
+	}
+
+	data, err := MarshalMetadataInfo(info)
+	assert.NoError(t, err)
+
+	got, err := UnmarshalMetadataInfo(data)
+	assert.NoError(t, err)
+	assert.Equal(t, info, got)
+}
+
+// TestMarshalMetadataDeltaRoundTrip covers MarshalMetadataDelta/UnmarshalMetadataDelta agreeing
+// with each other on a well-formed payload.
+func TestMarshalMetadataDeltaRoundTrip(t *testing.T) {
+	delta := &MetadataDelta{
+		ParentRevision: "r1",
+		Added:          map[string]*common.ServiceInfo{"svc": {Name: "svc"}},
+	}
+
+	data, err := MarshalMetadataDelta(delta)
+	assert.NoError(t, err)
+
+	got, err := UnmarshalMetadataDelta(data)
+	assert.NoError(t, err)
+	assert.Equal(t, delta, got)
+}
+
+// TestUnmarshalMetadataInfoRejectsDeltaPayload is the regression case the discriminator exists
+// for: a MetadataDelta and a MetadataInfo share no JSON field names, so decoding a delta's bytes
+// as a MetadataInfo used to succeed silently with a zero value instead of erroring. Reading a
+// MarshalMetadataDelta payload back with UnmarshalMetadataInfo must now fail instead.
+func TestUnmarshalMetadataInfoRejectsDeltaPayload(t *testing.T) {
+	data, err := MarshalMetadataDelta(&MetadataDelta{ParentRevision: "r1"})
+	assert.NoError(t, err)
+
+	_, err = UnmarshalMetadataInfo(data)
+	assert.Error(t, err)
+}
+
+// TestUnmarshalMetadataDeltaRejectsInfoPayload is the mirror image of
+// TestUnmarshalMetadataInfoRejectsDeltaPayload: reading a MarshalMetadataInfo payload back with
+// UnmarshalMetadataDelta must fail instead of silently yielding a zero-value MetadataDelta.
+func TestUnmarshalMetadataDeltaRejectsInfoPayload(t *testing.T) {
+	data, err := MarshalMetadataInfo(&common.MetadataInfo{})
+	assert.NoError(t, err)
+
+	_, err = UnmarshalMetadataDelta(data)
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"fmt"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/constant"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/config"
+	"github.com/apache/dubbo-go/metadata/service/proxy"
+	"github.com/apache/dubbo-go/registry"
+)
+
+// defaultProxyFactoryName is the name the built-in MetadataServiceProxyFactory registers under.
+const defaultProxyFactoryName = "default"
+
+func init() {
+	extension.SetMetadataServiceProxyFactory(defaultProxyFactoryName, newDefaultMetadataServiceProxyFactory)
+}
+
+func newDefaultMetadataServiceProxyFactory() proxy.MetadataServiceProxyFactory {
+	return &defaultMetadataServiceProxyFactory{}
+}
+
+type defaultMetadataServiceProxyFactory struct{}
+
+// metadataServiceConsumerStub is the consumer-side struct ReferenceConfig.Implement binds before
+// Refer resolves the invoker. GetMetadataInfoInvoker is the exported field Refer fills in by
+// reflection once the reference is live; GetMetadataInfo just delegates to it, which is what
+// lets the stub itself satisfy proxy.MetadataService for GetProxy's type assertion.
+type metadataServiceConsumerStub struct {
+	GetMetadataInfoInvoker func(revision string) (*common.MetadataInfo, error)
+}
+
+func (s *metadataServiceConsumerStub) GetMetadataInfo(revision string) (*common.MetadataInfo, error) {
+	return s.GetMetadataInfoInvoker(revision)
+}
+
+// GetProxy builds a one-off dubbo reference pointed directly at instance's address, referring
+// to the MetadataService interface it exports, and returns it as a proxy.MetadataService.
+func (f *defaultMetadataServiceProxyFactory) GetProxy(instance registry.ServiceInstance) (proxy.MetadataService, error) {
+	rc := config.NewReferenceConfigBuilder().
+		SetInterface(constant.METADATA_SERVICE_NAME).
+		SetProtocol(constant.DUBBO).
+		SetURL(fmt.Sprintf("%s://%s:%d", constant.DUBBO, instance.GetHost(), instance.GetPort())).
+		Build()
+	rc.Implement(&metadataServiceConsumerStub{})
+	rc.Refer(nil)
+
+	svc, ok := rc.GetRPCService().(proxy.MetadataService)
+	if !ok {
+		return nil, fmt.Errorf("exported MetadataService of instance %s:%d does not implement metadata/service/proxy.MetadataService", instance.GetHost(), instance.GetPort())
+	}
+	return svc, nil
+}
@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package local exports the instance's in-memory MetadataService as a real Dubbo RPC provider,
+// so a consumer configured with metadata-type=local can fetch metadata directly from the
+// provider instead of through a shared metadata/report backend.
+package local
+
+import (
+	"sync"
+)
+
+import (
+	"github.com/apache/dubbo-go/common/constant"
+	"github.com/apache/dubbo-go/common/logger"
+	"github.com/apache/dubbo-go/config"
+	"github.com/apache/dubbo-go/metadata/service/inmemory"
+	"github.com/apache/dubbo-go/metadata/service/proxy"
+	"github.com/apache/dubbo-go/registry"
+)
+
+// version will be used by Version func
+const version = "1.0.0"
+
+// defaultMetadataStorageType is used when the application has not set metadata-type, preserving
+// the shared-report behaviour that metadata-type=local is an opt-in alternative to.
+const defaultMetadataStorageType = "remote"
+
+// LocalMetadataService wraps the instance's in-memory MetadataService and exports it as a
+// Dubbo provider under the MetadataService interface name, so MetadataServiceProxyFactory can
+// build a consumer-side RPC proxy for it.
+type LocalMetadataService struct {
+	*inmemory.MetadataService
+	exporter *config.ServiceConfig
+}
+
+var (
+	localMetadataServiceOnce     sync.Once
+	localMetadataServiceInstance *LocalMetadataService
+)
+
+// GetLocalMetadataService returns the singleton LocalMetadataService, creating it on first call.
+func GetLocalMetadataService() (*LocalMetadataService, error) {
+	var err error
+	localMetadataServiceOnce.Do(func() {
+		// it will never return error
+		inms, _ := inmemory.GetInMemoryMetadataService()
+		localMetadataServiceInstance = &LocalMetadataService{
+			MetadataService: inms.(*inmemory.MetadataService),
+		}
+	})
+	return localMetadataServiceInstance, err
+}
+
+// Export registers the LocalMetadataService as a Dubbo provider so it can be invoked over RPC by
+// consumers whose metadata-type is "local". It is a no-op if already exported.
+func (l *LocalMetadataService) Export() error {
+	if l.exporter != nil {
+		return nil
+	}
+
+	sc := config.NewServiceConfigBuilder().
+		SetInterface(constant.METADATA_SERVICE_NAME).
+		SetProtocolIDs(constant.DUBBO).
+		SetRegistryIDs().
+		Build()
+	sc.Implement(l)
+	if err := sc.Export(); err != nil {
+		return err
+	}
+
+	l.exporter = sc
+	logger.Infof("exported LocalMetadataService as interface %s", constant.METADATA_SERVICE_NAME)
+	return nil
+}
+
+// Unexport stops serving the LocalMetadataService over RPC.
+func (l *LocalMetadataService) Unexport() {
+	if l.exporter == nil {
+		return
+	}
+	l.exporter.Unexport()
+	l.exporter = nil
+}
+
+// resolveMetadataStorageType falls back to defaultMetadataStorageType when the application has
+// left metadata-type blank, mirroring metadata/report/delegate's fallback for its own,
+// differently-scoped metadata-type setting.
+func resolveMetadataStorageType(configured string) string {
+	if configured == "" {
+		return defaultMetadataStorageType
+	}
+	return configured
+}
+
+// MetadataStorageType returns the application's configured metadata-type ("local" or "remote"),
+// defaulting to "remote" when unset.
+func MetadataStorageType() string {
+	return resolveMetadataStorageType(config.GetApplicationConfig().MetadataType)
+}
+
+// ExportIfConfigured exports the singleton LocalMetadataService and stamps instance's metadata
+// with proxy.MetadataStorageTypePropertyName=proxy.LocalMetadataStorageType when the application
+// is configured for metadata-type=local, so RemoteMetadataService.GetMetadata on the consumer
+// side knows to fetch instance's MetadataInfo directly over RPC instead of through the shared
+// report backend. Call it once per instance, after the instance's own metadata is otherwise
+// populated but before it is registered. It is a no-op, leaving instance untouched, when
+// metadata-type is "remote" (the default).
+func ExportIfConfigured(instance registry.ServiceInstance) error {
+	if MetadataStorageType() != proxy.LocalMetadataStorageType {
+		return nil
+	}
+
+	l, err := GetLocalMetadataService()
+	if err != nil {
+		return err
+	}
+	if err := l.Export(); err != nil {
+		return err
+	}
+
+	instance.GetMetadata()[proxy.MetadataStorageTypePropertyName] = proxy.LocalMetadataStorageType
+	return nil
+}
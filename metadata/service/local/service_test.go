@@ -0,0 +1,36 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+// TestResolveMetadataStorageType covers the fallback resolveMetadataStorageType applies when the
+// application hasn't set metadata-type, and that it otherwise passes an explicit setting through
+// unchanged (in particular, without normalizing its case).
+func TestResolveMetadataStorageType(t *testing.T) {
+	assert.Equal(t, defaultMetadataStorageType, resolveMetadataStorageType(""))
+	assert.Equal(t, "local", resolveMetadataStorageType("local"))
+	assert.Equal(t, "remote", resolveMetadataStorageType("remote"))
+	assert.Equal(t, "Local", resolveMetadataStorageType("Local"))
+}
@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package proxy holds the extension-point interfaces for building a consumer-side RPC proxy
+// onto a provider's local (in-instance) MetadataService, kept dependency-free of both
+// metadata/service/local and common/extension so each can import it without a cycle.
+package proxy
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/registry"
+)
+
+// MetadataStorageTypePropertyName is the registry.ServiceInstance metadata key a provider
+// publishes its metadata-type ("local" or "remote") under, so a consumer can tell whether to
+// fetch MetadataInfo from it directly over RPC or through the shared report backend. It lives
+// here, not in metadata/service/local or metadata/service/remote, so both can stamp/read it
+// without importing one another.
+const MetadataStorageTypePropertyName = "dubbo.metadata.storage-type"
+
+// LocalMetadataStorageType is the MetadataStorageTypePropertyName value a provider advertises
+// when it exports its MetadataService as a Dubbo RPC provider (see metadata/service/local)
+// instead of publishing to a shared report backend.
+const LocalMetadataStorageType = "local"
+
+// MetadataService is the subset of the full RPC MetadataService contract a consumer needs to
+// fetch a provider's MetadataInfo directly from it, bypassing the shared report backend.
+type MetadataService interface {
+	GetMetadataInfo(revision string) (*common.MetadataInfo, error)
+}
+
+// MetadataServiceProxyFactory builds a consumer-side RPC proxy for the MetadataService exported
+// by a given registry.ServiceInstance, for use when that instance's metadata-type is "local".
+type MetadataServiceProxyFactory interface {
+	GetProxy(instance registry.ServiceInstance) (MetadataService, error)
+}
@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMetadataChangeAdapterLastSeenRevisionConcurrent exercises lastSeenRevision being written
+// from two goroutines at once under lastSeenRevisionMu - the access pattern that arises when
+// delegate.MetadataReport.Subscribe registers the same adapter against more than one backend,
+// e.g. metadata-type "consul,etcd", and each backend's watch goroutine calls OnEvent
+// independently. Run with -race.
+func TestMetadataChangeAdapterLastSeenRevisionConcurrent(t *testing.T) {
+	adapter := &metadataChangeAdapter{serviceName: "svc"}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			adapter.lastSeenRevisionMu.Lock()
+			adapter.lastSeenRevision = "r-consul"
+			adapter.lastSeenRevisionMu.Unlock()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			adapter.lastSeenRevisionMu.Lock()
+			adapter.lastSeenRevision = "r-etcd"
+			adapter.lastSeenRevisionMu.Unlock()
+		}
+	}()
+	wg.Wait()
+
+	adapter.lastSeenRevisionMu.Lock()
+	final := adapter.lastSeenRevision
+	adapter.lastSeenRevisionMu.Unlock()
+	assert.NotEmpty(t, final)
+}
@@ -0,0 +1,172 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+import (
+	"golang.org/x/sync/singleflight"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+)
+
+// defaultMetadataCacheSize and defaultMetadataCacheTTL are used when
+// RemoteMetadataService.SetMetadataCacheOptions has not been called.
+const (
+	defaultMetadataCacheSize = 1024
+	defaultMetadataCacheTTL  = 10 * time.Minute
+)
+
+// metadataCacheMetrics are the counters exposed by RemoteMetadataService.MetadataCacheMetrics.
+type metadataCacheMetrics struct {
+	Hit               uint64
+	Miss              uint64
+	SingleflightDedup uint64
+}
+
+// metadataCache is a revision-keyed, size-bounded, TTL-expiring cache of *common.MetadataInfo,
+// with a singleflight.Group so concurrent misses for the same key collapse into a single
+// delegateReport read. Since the cache key embeds the revision and revisions are immutable once
+// published, a cached hit never needs re-validation.
+type metadataCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List // front = most recently used
+
+	group singleflight.Group
+
+	metricsMu sync.Mutex
+	metrics   metadataCacheMetrics
+}
+
+type metadataCacheEntry struct {
+	key       string
+	info      *common.MetadataInfo
+	expiresAt time.Time
+}
+
+func newMetadataCache(size int, ttl time.Duration) *metadataCache {
+	return &metadataCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the cached MetadataInfo for key, if present and not expired.
+func (c *metadataCache) get(key string) (*common.MetadataInfo, bool) {
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	var info *common.MetadataInfo
+	if ok {
+		entry := elem.Value.(*metadataCacheEntry)
+		if time.Now().After(entry.expiresAt) {
+			c.removeLocked(elem)
+			ok = false
+		} else {
+			c.order.MoveToFront(elem)
+			// Read info while still holding c.mu: elem.Value is replaced in place by a
+			// concurrent set() on the same key under this same lock, so reading it again
+			// after unlocking would race that write.
+			info = entry.info
+		}
+	}
+	c.mu.Unlock()
+
+	if ok {
+		c.recordHit()
+		return info, true
+	}
+	c.recordMiss()
+	return nil, false
+}
+
+// set stores info under key, evicting the least recently used entry if size is exceeded.
+func (c *metadataCache) set(key string, info *common.MetadataInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = &metadataCacheEntry{key: key, info: info, expiresAt: time.Now().Add(c.ttl)}
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&metadataCacheEntry{key: key, info: info, expiresAt: time.Now().Add(c.ttl)})
+	c.entries[key] = elem
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.removeLocked(oldest)
+	}
+}
+
+// invalidate drops key from the cache, if present.
+func (c *metadataCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.removeLocked(elem)
+	}
+}
+
+// removeLocked removes elem from both the map and the LRU list. c.mu must already be held.
+func (c *metadataCache) removeLocked(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*metadataCacheEntry).key)
+}
+
+func (c *metadataCache) recordHit() {
+	c.metricsMu.Lock()
+	c.metrics.Hit++
+	c.metricsMu.Unlock()
+}
+
+func (c *metadataCache) recordMiss() {
+	c.metricsMu.Lock()
+	c.metrics.Miss++
+	c.metricsMu.Unlock()
+}
+
+func (c *metadataCache) recordSingleflightDedup() {
+	c.metricsMu.Lock()
+	c.metrics.SingleflightDedup++
+	c.metricsMu.Unlock()
+}
+
+func (c *metadataCache) snapshotMetrics() metadataCacheMetrics {
+	c.metricsMu.Lock()
+	defer c.metricsMu.Unlock()
+	return c.metrics
+}
+
+// metadataCacheKey builds the cache/singleflight key for a (serviceName, revision) pair.
+func metadataCacheKey(serviceName, revision string) string {
+	return serviceName + ":" + revision
+}
@@ -0,0 +1,192 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+)
+
+// TestSetMetadataCacheOptionsConcurrentWithReaders exercises SetMetadataCacheOptions swapping
+// mts.metadataCache while MetadataCacheMetrics and InvalidateRevision are reading it
+// concurrently through the cacheMu-guarded cache() accessor. Run with -race.
+func TestSetMetadataCacheOptionsConcurrentWithReaders(t *testing.T) {
+	mts := &RemoteMetadataService{
+		metadataCache: newMetadataCache(defaultMetadataCacheSize, defaultMetadataCacheTTL),
+	}
+	mts.cache().set(metadataCacheKey("svc", "r1"), &common.MetadataInfo{})
+
+	var wg sync.WaitGroup
+	stop := time.After(20 * time.Millisecond)
+	wg.Add(3)
+
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				mts.SetMetadataCacheOptions(defaultMetadataCacheSize, defaultMetadataCacheTTL)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				mts.MetadataCacheMetrics()
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				mts.InvalidateRevision("svc", "r1")
+			}
+		}
+	}()
+
+	wg.Wait()
+	assert.NotNil(t, mts.cache())
+}
+
+// TestMetadataCacheLRUEviction fills the cache past size and checks that the least recently
+// used entry - not just the oldest-inserted one - is the one evicted: touching "a" via get()
+// before inserting past capacity should save it from eviction, so "b" is evicted in its place.
+func TestMetadataCacheLRUEviction(t *testing.T) {
+	c := newMetadataCache(2, defaultMetadataCacheTTL)
+	c.set("a", &common.MetadataInfo{})
+	c.set("b", &common.MetadataInfo{})
+
+	// Touch "a" so it becomes more recently used than "b".
+	_, ok := c.get("a")
+	assert.True(t, ok)
+
+	c.set("c", &common.MetadataInfo{})
+
+	_, ok = c.get("b")
+	assert.False(t, ok, "b should have been evicted as the least recently used entry")
+	_, ok = c.get("a")
+	assert.True(t, ok, "a should have survived eviction since it was touched more recently than b")
+	_, ok = c.get("c")
+	assert.True(t, ok, "c should be present as the just-inserted entry")
+}
+
+// TestMetadataCacheTTLExpiry checks that get() treats an entry past its TTL as a miss and drops
+// it, rather than serving stale data.
+func TestMetadataCacheTTLExpiry(t *testing.T) {
+	c := newMetadataCache(defaultMetadataCacheSize, time.Millisecond)
+	c.set("a", &common.MetadataInfo{})
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := c.get("a")
+	assert.False(t, ok)
+}
+
+// TestMetadataCacheMetrics checks that Hit, Miss and SingleflightDedup on a metadataCache count
+// exactly what happened: a miss on first get(), a hit on the second, and a dedup is only
+// recordSingleflightDedup's caller's responsibility to report - verified separately from get/set
+// since metadataCache itself never calls group.Do.
+func TestMetadataCacheMetrics(t *testing.T) {
+	c := newMetadataCache(defaultMetadataCacheSize, defaultMetadataCacheTTL)
+
+	_, ok := c.get("a")
+	assert.False(t, ok)
+
+	c.set("a", &common.MetadataInfo{})
+	_, ok = c.get("a")
+	assert.True(t, ok)
+
+	c.recordSingleflightDedup()
+
+	metrics := c.snapshotMetrics()
+	assert.EqualValues(t, 1, metrics.Hit)
+	assert.EqualValues(t, 1, metrics.Miss)
+	assert.EqualValues(t, 1, metrics.SingleflightDedup)
+}
+
+// TestMetadataCacheInvalidate checks that invalidate() drops the entry so a subsequent get()
+// reports a miss instead of still serving the now-invalidated value.
+func TestMetadataCacheInvalidate(t *testing.T) {
+	c := newMetadataCache(defaultMetadataCacheSize, defaultMetadataCacheTTL)
+	c.set("a", &common.MetadataInfo{})
+
+	_, ok := c.get("a")
+	assert.True(t, ok)
+
+	c.invalidate("a")
+
+	_, ok = c.get("a")
+	assert.False(t, ok)
+}
+
+// TestMetadataCacheGetSetConcurrentSameKey drives concurrent get/set on a single shared key, so
+// a get() reading elem.Value again after releasing c.mu would race a concurrent set() mutating
+// that same elem.Value in place. Run with -race.
+func TestMetadataCacheGetSetConcurrentSameKey(t *testing.T) {
+	c := newMetadataCache(defaultMetadataCacheSize, defaultMetadataCacheTTL)
+	key := metadataCacheKey("svc", "r1")
+	c.set(key, &common.MetadataInfo{})
+
+	var wg sync.WaitGroup
+	stop := time.After(20 * time.Millisecond)
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				c.set(key, &common.MetadataInfo{})
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				c.get(key)
+			}
+		}
+	}()
+
+	wg.Wait()
+}
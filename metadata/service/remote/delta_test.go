@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+)
+
+// TestApplyDeltaChain reproduces the two-hop scenario a single publishDelta/assembleMetadata
+// round can't cover: a full snapshot {a,b}, a first delta that adds c, and a second delta -
+// diffed against the first delta's result, not the snapshot - that changes a. Replaying both
+// deltas in order on top of the snapshot must keep c; replaying only the second delta directly
+// on top of the snapshot (skipping the first) would silently drop it.
+func TestApplyDeltaChain(t *testing.T) {
+	snapshot := &common.MetadataInfo{
+		Services: map[string]*common.ServiceInfo{
+			"a": {Name: "a"},
+			"b": {Name: "b"},
+		},
+	}
+
+	aChanged := &common.ServiceInfo{Name: "a-v2"}
+
+	afterDelta1 := &common.MetadataInfo{
+		Services: map[string]*common.ServiceInfo{
+			"a": {Name: "a"},
+			"b": {Name: "b"},
+			"c": {Name: "c"},
+		},
+	}
+	delta1 := diffMetadataInfo("r0", snapshot, afterDelta1)
+	assert.Len(t, delta1.Added, 1)
+	assert.Contains(t, delta1.Added, "c")
+	assert.Empty(t, delta1.Changed)
+
+	afterDelta2 := &common.MetadataInfo{
+		Services: map[string]*common.ServiceInfo{
+			"a": aChanged,
+			"b": {Name: "b"},
+			"c": {Name: "c"},
+		},
+	}
+	delta2 := diffMetadataInfo("r1", afterDelta1, afterDelta2)
+	assert.Equal(t, "r1", delta2.ParentRevision)
+	assert.Len(t, delta2.Changed, 1)
+	assert.Contains(t, delta2.Changed, "a")
+
+	// Replaying the full chain (snapshot -> delta1 -> delta2), as assembleMetadata does when
+	// every delta's ParentRevision points to the hop immediately before it, must keep "c".
+	replayed := applyDelta(applyDelta(snapshot, delta1), delta2)
+	assert.Len(t, replayed.Services, 3)
+	assert.Contains(t, replayed.Services, "c")
+	assert.Same(t, aChanged, replayed.Services["a"])
+
+	// Applying delta2 directly on top of the snapshot - what happened when ParentRevision was
+	// wrongly stamped with the last full-snapshot revision instead of the previous hop - drops c.
+	skippingDelta1 := applyDelta(snapshot, delta2)
+	assert.NotContains(t, skippingDelta1.Services, "c")
+}
+
+// TestSnapshotMetadataInfoPreventsSelfDiff reproduces what happens once PublishMetadata keeps
+// the same *common.MetadataInfo inMemoryMetadataService mutates in place: without
+// snapshotMetadataInfo, mts.lastPublished and the live info become the same pointer/map, so
+// diffing one against the other after a service is added diffs the map against itself and
+// always comes back empty.
+func TestSnapshotMetadataInfoPreventsSelfDiff(t *testing.T) {
+	live := &common.MetadataInfo{
+		Services: map[string]*common.ServiceInfo{
+			"a": {Name: "a"},
+		},
+	}
+
+	lastPublished := snapshotMetadataInfo(live)
+
+	// inMemoryMetadataService.AddService (or equivalent) mutates the live MetadataInfo's
+	// Services map in place - simulated here by adding directly into live.Services.
+	live.Services["b"] = &common.ServiceInfo{Name: "b"}
+
+	delta := diffMetadataInfo("r0", lastPublished, live)
+	assert.Len(t, delta.Added, 1)
+	assert.Contains(t, delta.Added, "b")
+
+	// Diffing the live, mutated map against itself - the bug this guards against - always
+	// comes back empty, which is exactly how it silently shipped stale data.
+	selfDiff := diffMetadataInfo("r0", live, live)
+	assert.Empty(t, selfDiff.Added)
+	assert.Empty(t, selfDiff.Changed)
+	assert.Empty(t, selfDiff.Removed)
+}
@@ -0,0 +1,75 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"sync"
+)
+
+import (
+	"github.com/apache/dubbo-go/common/constant"
+	"github.com/apache/dubbo-go/common/logger"
+	"github.com/apache/dubbo-go/registry"
+)
+
+// InstanceRefreshListener is the concrete registry.ServiceInstancesChangedListener integration
+// hook RefreshMetadataOnInstanceChange was added for: register one per service with the registry
+// layer so every protocol shares the same revision comparison and refresh path, instead of each
+// one re-deriving it against EXPORTED_SERVICES_REVISION_PROPERTY_NAME on its own.
+type InstanceRefreshListener struct {
+	mts *RemoteMetadataService
+
+	mu            sync.Mutex
+	lastRevisions map[string]string // serviceName -> last-seen EXPORTED_SERVICES_REVISION_PROPERTY_NAME
+}
+
+// NewInstanceRefreshListener returns an InstanceRefreshListener backed by mts.
+func NewInstanceRefreshListener(mts *RemoteMetadataService) *InstanceRefreshListener {
+	return &InstanceRefreshListener{
+		mts:           mts,
+		lastRevisions: make(map[string]string),
+	}
+}
+
+// OnInstancesChanged is what the registry layer calls whenever the instance list behind
+// serviceName changes. For every instance whose advertised revision differs from the one last
+// seen for serviceName, it refreshes the reassembled MetadataInfo via
+// RefreshMetadataOnInstanceChange, so a stale revision observed once doesn't need every
+// subsequent caller to notice the change and refresh it independently.
+func (l *InstanceRefreshListener) OnInstancesChanged(serviceName string, instances []registry.ServiceInstance) error {
+	for _, instance := range instances {
+		revision := instance.GetMetadata()[constant.EXPORTED_SERVICES_REVISION_PROPERTY_NAME]
+		if revision == "" {
+			continue
+		}
+
+		l.mu.Lock()
+		previous, seen := l.lastRevisions[serviceName]
+		l.lastRevisions[serviceName] = revision
+		l.mu.Unlock()
+
+		if seen && previous == revision {
+			continue
+		}
+
+		if _, err := l.mts.RefreshMetadataOnInstanceChange(serviceName, revision); err != nil {
+			logger.Errorf("refreshing metadata for service %s at revision %s error[%v]", serviceName, revision, err)
+		}
+	}
+	return nil
+}
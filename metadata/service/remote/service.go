@@ -18,8 +18,10 @@
 package remote
 
 import (
-	"github.com/apache/dubbo-go/registry"
+	"fmt"
+	"reflect"
 	"sync"
+	"time"
 )
 
 import (
@@ -29,11 +31,15 @@ import (
 import (
 	"github.com/apache/dubbo-go/common"
 	"github.com/apache/dubbo-go/common/constant"
+	"github.com/apache/dubbo-go/common/extension"
 	"github.com/apache/dubbo-go/common/logger"
 	"github.com/apache/dubbo-go/metadata/definition"
 	"github.com/apache/dubbo-go/metadata/identifier"
+	"github.com/apache/dubbo-go/metadata/report"
 	"github.com/apache/dubbo-go/metadata/report/delegate"
 	"github.com/apache/dubbo-go/metadata/service/inmemory"
+	"github.com/apache/dubbo-go/metadata/service/proxy"
+	"github.com/apache/dubbo-go/registry"
 )
 
 // version will be used by Version func
@@ -42,13 +48,78 @@ const (
 	remote  = "remote"
 )
 
+// fullSnapshotInterval bounds how many incremental deltas may be published in a row before
+// PublishMetadata forces a full snapshot, so a GetMetadata caller never has to replay an
+// unbounded delta chain.
+const fullSnapshotInterval = 20
+
+// pointerRevision is the fixed revision PublishMetadata writes the RevisionPointer under, a
+// mutable marker distinct from the immutable per-revision MetadataInfo/MetadataDelta blobs,
+// so Subscribe has a stable key to watch for new revisions.
+const pointerRevision = "pointer"
+
+// defaultMetadataServiceProxyFactoryName is the name metadata/service/local registers its
+// built-in MetadataServiceProxyFactory under.
+const defaultMetadataServiceProxyFactoryName = "default"
+
+// MetadataChangeListener is notified with the freshly reassembled MetadataInfo whenever a
+// provider this service is Subscribed to republishes under a new revision.
+type MetadataChangeListener interface {
+	OnMetadataChanged(serviceName string, info *common.MetadataInfo) error
+}
+
+// subscriptionKey identifies one Subscribe registration, so Unsubscribe can find the
+// report.MetadataChangeListener adapter it needs to cancel.
+type subscriptionKey struct {
+	serviceName string
+	listener    MetadataChangeListener
+}
+
 // MetadataService is a implement of metadata service which will delegate the remote metadata report
 // This is singleton
 type RemoteMetadataService struct {
 	inMemoryMetadataService *inmemory.MetadataService
 	exportedRevision        atomic.String
 	subscribedRevision      atomic.String
-	delegateReport          *delegate.MetadataReport
+	// delegateReport dispatches to whichever backend(s) `metadata-type` selects,
+	// e.g. zookeeper, nacos, consul or etcd. See metadata/report/delegate.
+	delegateReport *delegate.MetadataReport
+
+	// publishMutex guards lastPublished, lastPublishedRevision and deltaCount below,
+	// which together track the incremental publish state of PublishMetadata.
+	publishMutex sync.Mutex
+	// lastPublished is the last in-memory MetadataInfo snapshot that was fully or
+	// incrementally published, used as the diff base for the next publish.
+	lastPublished *common.MetadataInfo
+	// lastPublishedRevision is the revision lastPublished was written under, whether that
+	// write was a full snapshot or a delta. Every publishDelta links its MetadataDelta's
+	// ParentRevision to this, so assembleMetadata can walk the chain hop by hop back to the
+	// nearest full snapshot instead of skipping straight to it.
+	lastPublishedRevision string
+	// deltaCount is how many incremental deltas have been published since the last full snapshot
+	deltaCount int
+
+	// subscriptionsMu guards subscriptions below.
+	subscriptionsMu sync.Mutex
+	// subscriptions tracks the report.MetadataChangeListener adapter registered for each
+	// Subscribe call, so Unsubscribe can cancel the matching backend watch.
+	subscriptions map[subscriptionKey]report.MetadataChangeListener
+
+	// cacheMu guards metadataCache below, so SetMetadataCacheOptions can swap it out for a
+	// fresh one without racing a concurrent getMetadataCached/MetadataCacheMetrics/
+	// InvalidateRevision reading the old pointer.
+	cacheMu sync.RWMutex
+	// metadataCache caches GetMetadata results by serviceName+revision, so a cluster of N
+	// consumers under M providers doesn't turn every instance refresh into an N*M report read.
+	metadataCache *metadataCache
+}
+
+// cache returns the current metadataCache, synchronized against a concurrent
+// SetMetadataCacheOptions swapping it out.
+func (mts *RemoteMetadataService) cache() *metadataCache {
+	mts.cacheMu.RLock()
+	defer mts.cacheMu.RUnlock()
+	return mts.metadataCache
 }
 
 var (
@@ -70,31 +141,333 @@ func GetRemoteMetadataService() (*RemoteMetadataService, error) {
 		metadataServiceInstance = &RemoteMetadataService{
 			inMemoryMetadataService: inms.(*inmemory.MetadataService),
 			delegateReport:          mr,
+			subscriptions:           make(map[subscriptionKey]report.MetadataChangeListener),
+			metadataCache:           newMetadataCache(defaultMetadataCacheSize, defaultMetadataCacheTTL),
 		}
 	})
 	return metadataServiceInstance, err
 }
 
-// publishMetadata
+// PublishMetadata publishes the current in-memory MetadataInfo. Once a parent revision has
+// been published, subsequent calls write only the service entries that changed since then as
+// a report.MetadataDelta, until fullSnapshotInterval is reached and a full snapshot is forced
+// again, keeping the store's write amplification low for applications with many services.
 func (mts *RemoteMetadataService) PublishMetadata(service string) {
 	info := mts.inMemoryMetadataService.GetMetadataInfo("")
 	if info.HasReported() {
 		return
 	}
-	id := identifier.NewSubscriberMetadataIdentifier(service, info.CalAndGetRevision())
-	err := mts.delegateReport.PublishAppMetadata(id, info)
+
+	mts.publishMutex.Lock()
+	defer mts.publishMutex.Unlock()
+
+	revision := info.CalAndGetRevision()
+	var err error
+	if mts.lastPublished == nil || mts.deltaCount >= fullSnapshotInterval {
+		err = mts.publishFullSnapshot(service, revision, info)
+	} else {
+		err = mts.publishDelta(service, revision, info)
+	}
 	if err != nil {
 		logger.Errorf("Publishing metadata to error[%v]", err)
 		return
 	}
+
+	pointerID := identifier.NewSubscriberMetadataIdentifier(service, pointerRevision)
+	if err := mts.delegateReport.PublishRevisionPointer(pointerID, &report.RevisionPointer{Revision: revision}); err != nil {
+		logger.Errorf("Publishing revision pointer error[%v]", err)
+		return
+	}
+
+	// Only advance lastPublished/lastPublishedRevision once the pointer write has also
+	// succeeded. Advancing them right after publishFullSnapshot/publishDelta, as before,
+	// left the pair pointing at revision even when the pointer write then failed; the next
+	// PublishMetadata call would recompute the same revision from the same unchanged info,
+	// diff it against a snapshot already at that revision (an empty delta), and stamp that
+	// delta's ParentRevision with a lastPublishedRevision equal to its own identifier -
+	// overwriting the previously-good delta with one that makes assembleMetadata recurse
+	// into itself forever.
+	//
+	// Snapshot a copy of info's services rather than keeping info itself: info is the live,
+	// in-place-mutated MetadataInfo behind inMemoryMetadataService (that's what lets
+	// HasReported/MarkReported work at all), so once a service is added or removed it would
+	// change underneath mts.lastPublished too - the next publishDelta would diff info against
+	// itself and always see no changes.
+	mts.lastPublished = snapshotMetadataInfo(info)
+	mts.lastPublishedRevision = revision
+
+	// Mark reported only once the pointer write has actually succeeded. Marking it any
+	// earlier would leave HasReported() true after a pointer failure, and since
+	// PublishMetadata bails out on HasReported() at the top, the next call for this
+	// service would silently skip publishing forever instead of retrying.
 	info.MarkReported()
 }
 
-// publishMetadata
+// Subscribe watches service for new revisions, invoking listener with the freshly reassembled
+// MetadataInfo whenever the provider republishes under a new revision. It is the pull-to-push
+// counterpart of GetMetadata, translating to each configured metadata-type backend's own watch:
+// an Etcd watch for etcd or Consul blocking queries for consul. Those two are the only report
+// backends in this tree; there is no zookeeper or nacos backend to watch.
+func (mts *RemoteMetadataService) Subscribe(serviceName string, listener MetadataChangeListener) error {
+	adapter := &metadataChangeAdapter{mts: mts, serviceName: serviceName, listener: listener}
+	pointerID := identifier.NewSubscriberMetadataIdentifier(serviceName, pointerRevision)
+	if err := mts.delegateReport.Subscribe(pointerID, adapter); err != nil {
+		return err
+	}
+
+	mts.subscriptionsMu.Lock()
+	mts.subscriptions[subscriptionKey{serviceName: serviceName, listener: listener}] = adapter
+	mts.subscriptionsMu.Unlock()
+	return nil
+}
+
+// Unsubscribe cancels a watch previously registered with Subscribe for the same serviceName and listener.
+func (mts *RemoteMetadataService) Unsubscribe(serviceName string, listener MetadataChangeListener) error {
+	key := subscriptionKey{serviceName: serviceName, listener: listener}
+
+	mts.subscriptionsMu.Lock()
+	adapter, ok := mts.subscriptions[key]
+	delete(mts.subscriptions, key)
+	mts.subscriptionsMu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	pointerID := identifier.NewSubscriberMetadataIdentifier(serviceName, pointerRevision)
+	return mts.delegateReport.Unsubscribe(pointerID, adapter)
+}
+
+// RefreshMetadataOnInstanceChange re-fetches (or reassembles) the MetadataInfo for serviceName
+// at revision through the same getMetadataCached path GetMetadata uses, so the refresh actually
+// warms mts.metadataCache instead of doing a throwaway read a subsequent consumer GetMetadata
+// call would just have to repeat. InstanceRefreshListener is the
+// registry.ServiceInstancesChangedListener built on top of this method, so the registry layer
+// can trigger a metadata refresh in one place instead of each protocol re-implementing the same
+// revision comparison against EXPORTED_SERVICES_REVISION_PROPERTY_NAME.
+func (mts *RemoteMetadataService) RefreshMetadataOnInstanceChange(serviceName, revision string) (*common.MetadataInfo, error) {
+	return mts.getMetadataCached(serviceName, revision)
+}
+
+// metadataChangeAdapter adapts a report.MetadataChangeListener (revision-only) notification
+// from the delegate report into a MetadataChangeListener (full MetadataInfo) callback, by
+// reassembling the MetadataInfo for the new revision before forwarding it. It also invalidates
+// the cache entry for the revision it last saw, since the backend watch that drives OnEvent is
+// exactly where serviceName's EXPORTED_SERVICES_REVISION_PROPERTY_NAME is observed to change.
+type metadataChangeAdapter struct {
+	mts         *RemoteMetadataService
+	serviceName string
+	listener    MetadataChangeListener
+
+	// lastSeenRevisionMu guards lastSeenRevision below. A single Subscribe call registers this
+	// same adapter against every backend delegateReport fans out to (see
+	// delegate.MetadataReport.Subscribe), so with metadata-type set to more than one backend,
+	// e.g. "consul,etcd", two independent watch goroutines can call OnEvent concurrently.
+	lastSeenRevisionMu sync.Mutex
+	lastSeenRevision   string
+}
+
+func (a *metadataChangeAdapter) OnEvent(revision string) error {
+	a.lastSeenRevisionMu.Lock()
+	previous := a.lastSeenRevision
+	a.lastSeenRevision = revision
+	a.lastSeenRevisionMu.Unlock()
+
+	if previous != "" && previous != revision {
+		a.mts.InvalidateRevision(a.serviceName, previous)
+	}
+
+	info, err := a.mts.assembleMetadata(a.serviceName, revision)
+	if err != nil {
+		return err
+	}
+	return a.listener.OnMetadataChanged(a.serviceName, info)
+}
+
+// publishFullSnapshot writes info in full under revision and resets the delta chain so future
+// publishDelta calls diff against it.
+func (mts *RemoteMetadataService) publishFullSnapshot(service, revision string, info *common.MetadataInfo) error {
+	id := identifier.NewSubscriberMetadataIdentifier(service, revision)
+	if err := mts.delegateReport.PublishAppMetadata(id, info); err != nil {
+		return err
+	}
+	mts.deltaCount = 0
+	return nil
+}
+
+// publishDelta diffs info against mts.lastPublished and writes only the added, changed and
+// removed service entries under revision, linking the stored delta's ParentRevision back to
+// mts.lastPublishedRevision — the revision lastPublished was itself written under, be that a
+// full snapshot or another delta. Stamping anything other than the immediately preceding
+// revision here would let assembleMetadata skip intermediate deltas when it replays the chain.
+func (mts *RemoteMetadataService) publishDelta(service, revision string, info *common.MetadataInfo) error {
+	delta := diffMetadataInfo(mts.lastPublishedRevision, mts.lastPublished, info)
+	id := identifier.NewSubscriberMetadataIdentifier(service, revision)
+	if err := mts.delegateReport.StoreMetadataDelta(id, delta); err != nil {
+		return err
+	}
+	mts.deltaCount++
+	return nil
+}
+
+// snapshotMetadataInfo copies info's Services map into a new MetadataInfo, so holding on to the
+// result remains a point-in-time view even if info itself keeps being mutated in place (as
+// inMemoryMetadataService's does whenever a service is added or removed).
+func snapshotMetadataInfo(info *common.MetadataInfo) *common.MetadataInfo {
+	services := make(map[string]*common.ServiceInfo, len(info.Services))
+	for key, svc := range info.Services {
+		services[key] = svc
+	}
+	return &common.MetadataInfo{Services: services}
+}
+
+// diffMetadataInfo computes the service entries that distinguish curr from prev, relative to parentRevision.
+func diffMetadataInfo(parentRevision string, prev, curr *common.MetadataInfo) *report.MetadataDelta {
+	delta := &report.MetadataDelta{
+		ParentRevision: parentRevision,
+		Added:          make(map[string]*common.ServiceInfo),
+		Changed:        make(map[string]*common.ServiceInfo),
+	}
+	for key, svc := range curr.Services {
+		old, ok := prev.Services[key]
+		if !ok {
+			delta.Added[key] = svc
+		} else if !reflect.DeepEqual(old, svc) {
+			delta.Changed[key] = svc
+		}
+	}
+	for key := range prev.Services {
+		if _, ok := curr.Services[key]; !ok {
+			delta.Removed = append(delta.Removed, key)
+		}
+	}
+	return delta
+}
+
+// GetMetadata fetches the MetadataInfo of instance, dispatching to the implementation
+// matching its advertised metadata-type: a direct RPC call through
+// metadata/service/proxy.MetadataServiceProxyFactory for "local", or the shared report backend
+// (with delta reassembly) for "remote".
 func (mts *RemoteMetadataService) GetMetadata(instance registry.ServiceInstance) (*common.MetadataInfo, error) {
 	revision := instance.GetMetadata()[constant.EXPORTED_SERVICES_REVISION_PROPERTY_NAME]
-	id := identifier.NewSubscriberMetadataIdentifier(instance.GetServiceName(), revision)
-	return mts.delegateReport.GetAppMetadata(id)
+	if instance.GetMetadata()[proxy.MetadataStorageTypePropertyName] == proxy.LocalMetadataStorageType {
+		return mts.getMetadataFromLocal(instance, revision)
+	}
+	return mts.getMetadataCached(instance.GetServiceName(), revision)
+}
+
+// getMetadataCached serves assembleMetadata results out of mts.metadataCache, keyed by
+// serviceName+revision. Concurrent misses for the same key are coalesced through a
+// singleflight.Group so a cluster under churn only issues one delegateReport read per
+// (service, revision), no matter how many callers ask for it at once.
+func (mts *RemoteMetadataService) getMetadataCached(serviceName, revision string) (*common.MetadataInfo, error) {
+	cache := mts.cache()
+	key := metadataCacheKey(serviceName, revision)
+	if info, ok := cache.get(key); ok {
+		return info, nil
+	}
+
+	v, err, shared := cache.group.Do(key, func() (interface{}, error) {
+		return mts.assembleMetadata(serviceName, revision)
+	})
+	if shared {
+		cache.recordSingleflightDedup()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := v.(*common.MetadataInfo)
+	cache.set(key, info)
+	return info, nil
+}
+
+// SetMetadataCacheOptions resizes and re-TTLs the GetMetadata cache. It discards any entries
+// already cached; call it once during startup before traffic ramps up.
+func (mts *RemoteMetadataService) SetMetadataCacheOptions(size int, ttl time.Duration) {
+	mts.cacheMu.Lock()
+	mts.metadataCache = newMetadataCache(size, ttl)
+	mts.cacheMu.Unlock()
+}
+
+// MetadataCacheMetrics reports the GetMetadata cache's cumulative hit, miss and
+// singleflight-dedup counts.
+func (mts *RemoteMetadataService) MetadataCacheMetrics() (hit, miss, singleflightDedup uint64) {
+	m := mts.cache().snapshotMetrics()
+	return m.Hit, m.Miss, m.SingleflightDedup
+}
+
+// InvalidateRevision evicts the cached MetadataInfo for serviceName at revision. The
+// metadataChangeAdapter installed by Subscribe calls this automatically once it observes
+// serviceName move on to a new revision, so a stale entry doesn't linger until it's naturally
+// evicted by TTL or LRU pressure.
+func (mts *RemoteMetadataService) InvalidateRevision(serviceName, revision string) {
+	mts.cache().invalidate(metadataCacheKey(serviceName, revision))
+}
+
+// getMetadataFromLocal fetches instance's MetadataInfo directly over RPC via the registered
+// MetadataServiceProxyFactory, used when instance advertises metadata-type=local.
+func (mts *RemoteMetadataService) getMetadataFromLocal(instance registry.ServiceInstance, revision string) (*common.MetadataInfo, error) {
+	factory, err := extension.GetMetadataServiceProxyFactory(defaultMetadataServiceProxyFactoryName)
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := factory.GetProxy(instance)
+	if err != nil {
+		return nil, err
+	}
+	return proxy.GetMetadataInfo(revision)
+}
+
+// assembleMetadata fetches the MetadataInfo published under revision, transparently walking
+// back through any report.MetadataDelta chain to the parent full snapshot and replaying the
+// deltas on top of it when revision itself was published incrementally.
+func (mts *RemoteMetadataService) assembleMetadata(serviceName, revision string) (*common.MetadataInfo, error) {
+	return mts.assembleMetadataChain(serviceName, revision, make(map[string]bool))
+}
+
+// assembleMetadataChain is assembleMetadata's recursive step. visited tracks the revisions
+// already walked in this call chain so a corrupt or racily-overwritten MetadataDelta - e.g. one
+// whose ParentRevision points back at itself or at an ancestor - fails with an error instead of
+// recursing forever.
+func (mts *RemoteMetadataService) assembleMetadataChain(serviceName, revision string, visited map[string]bool) (*common.MetadataInfo, error) {
+	id := identifier.NewSubscriberMetadataIdentifier(serviceName, revision)
+	if info, err := mts.delegateReport.GetAppMetadata(id); err == nil {
+		return info, nil
+	}
+
+	delta, err := mts.delegateReport.GetMetadataDelta(id)
+	if err != nil {
+		return nil, err
+	}
+	if visited[revision] || delta.ParentRevision == revision {
+		return nil, fmt.Errorf("metadata delta chain for service %s cycles back to revision %q", serviceName, revision)
+	}
+	visited[revision] = true
+
+	base, err := mts.assembleMetadataChain(serviceName, delta.ParentRevision, visited)
+	if err != nil {
+		return nil, err
+	}
+	return applyDelta(base, delta), nil
+}
+
+// applyDelta returns the MetadataInfo obtained by replaying delta on top of base, without
+// mutating base.
+func applyDelta(base *common.MetadataInfo, delta *report.MetadataDelta) *common.MetadataInfo {
+	services := make(map[string]*common.ServiceInfo, len(base.Services)+len(delta.Added))
+	for key, svc := range base.Services {
+		services[key] = svc
+	}
+	for _, key := range delta.Removed {
+		delete(services, key)
+	}
+	for key, svc := range delta.Added {
+		services[key] = svc
+	}
+	for key, svc := range delta.Changed {
+		services[key] = svc
+	}
+	return &common.MetadataInfo{Services: services}
 }
 
 // PublishServiceDefinition will call remote metadata's StoreProviderMetadata to store url info and service definition